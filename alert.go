@@ -0,0 +1,37 @@
+package main
+
+import "context"
+
+// Severity levels for an Alert.
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert describes a single low-disk-space event to be delivered by a
+// Notifier.
+type Alert struct {
+	Mountpoint string
+	FreeBytes  uint64
+	TotalBytes uint64
+	Percent    float64
+	Hostname   string
+	Severity   string
+	Message    string
+}
+
+// A Notifier delivers an Alert to some destination: a terminal, a
+// chat channel, an HTTP endpoint, a mail inbox, or the system log.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// percentFree returns how much of total is still free, as a
+// percentage. It returns 0 for an unknown (zero) total rather than
+// dividing by zero.
+func percentFree(free, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(free) / float64(total) * 100
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// maxAlertBodyBytes caps the size of an incoming /alert request body,
+// so an unauthenticated caller can't exhaust memory with an oversized
+// payload.
+const maxAlertBodyBytes = 1 << 20 // 1 MiB
+
+// alertmanagerWebhook mirrors the subset of Alertmanager's webhook
+// payload (see the Alertmanager docs on "Webhook") that diskmoose
+// needs to re-broadcast an alert through its own notifiers.
+type alertmanagerWebhook struct {
+	Status string              `json:"status"`
+	Alerts []alertmanagerAlert `json:"alerts"`
+}
+
+type alertmanagerAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// handleAlertmanagerWebhook accepts arbitrary Alertmanager alerts on
+// /alert and re-broadcasts each one through every configured
+// notifier, turning the moose into a generic notification sink.
+func handleAlertmanagerWebhook(notifiers []Notifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxAlertBodyBytes)
+		var payload alertmanagerWebhook
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+		for _, a := range payload.Alerts {
+			msg := a.Annotations["summary"]
+			if msg == "" {
+				msg = a.Labels["alertname"]
+			}
+			severity := SeverityWarning
+			if a.Labels["severity"] == "critical" {
+				severity = SeverityCritical
+			}
+			alert := Alert{Message: msg, Severity: severity}
+			for _, notifier := range notifiers {
+				if err := notifier.Notify(r.Context(), alert); err != nil {
+					log.Println("notifier failed:", err)
+				}
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
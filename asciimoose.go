@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mooseArt is a pure-Go stand-in for `cowsay -f moose`'s moose,
+// used when COWSAYCMD isn't installed so diskmoose still has
+// something to say on a box without cowsay.
+const mooseArt = `        \_\_    _/_/
+             \__/
+             (oo)\_______
+             (__)\       )\/\
+                 ||----w |
+                 ||     ||`
+
+// mooseSaysEmbedded renders msg in a cowsay-style speech bubble above
+// mooseArt.
+func mooseSaysEmbedded(msg string) string {
+	lines := wrapText(msg, 40)
+	width := 0
+	for _, line := range lines {
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, " %s\n", strings.Repeat("_", width+2))
+	for i, line := range lines {
+		left, right := "|", "|"
+		switch {
+		case len(lines) == 1:
+			left, right = "<", ">"
+		case i == 0:
+			left, right = "/", "\\"
+		case i == len(lines)-1:
+			left, right = "\\", "/"
+		}
+		fmt.Fprintf(&b, "%s %-*s %s\n", left, width, line, right)
+	}
+	fmt.Fprintf(&b, " %s\n", strings.Repeat("-", width+2))
+	fmt.Fprintln(&b, mooseArt)
+	return b.String()
+}
+
+// wrapText breaks msg into lines of at most width characters, on
+// word boundaries.
+func wrapText(msg string, width int) []string {
+	words := strings.Fields(msg)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	lines := make([]string, 0)
+	line := words[0]
+	for _, word := range words[1:] {
+		if len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
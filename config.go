@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the optional YAML configuration file (-config) that
+// selects which mountpoints and notifiers are active and how they
+// are set up.
+type Config struct {
+	// Min is the default low-disk-space threshold, overriding -min's
+	// default. An explicit -min on the command line still wins over
+	// this.
+	Min        string           `yaml:"min"`
+	Mounts     []string         `yaml:"mounts"`
+	Thresholds []MountThreshold `yaml:"thresholds"`
+	// IncludeFSTypes lists filesystem types (e.g. "tmpfs") that are
+	// normally skipped as pseudo filesystems but should be watched
+	// anyway.
+	IncludeFSTypes []string        `yaml:"include_fstypes"`
+	Notifiers      NotifiersConfig `yaml:"notifiers"`
+}
+
+// MountThreshold overrides the default threshold for mountpoints
+// matching Pattern (a filepath.Match glob, see isRelevant). The first
+// matching entry wins.
+type MountThreshold struct {
+	Pattern string `yaml:"pattern"`
+	Min     string `yaml:"min"`
+	Inodes  uint64 `yaml:"inodes"`
+}
+
+// NotifiersConfig lists the notifiers to enable. A notifier is
+// enabled by being present in the config (even with an empty body);
+// an omitted notifier stays disabled. Several can be active at once.
+type NotifiersConfig struct {
+	Pts      *struct{}      `yaml:"pts"`
+	Wall     *struct{}      `yaml:"wall"`
+	Webhook  *WebhookConfig `yaml:"webhook"`
+	Slack    *SlackConfig   `yaml:"slack"`
+	SMTP     *SMTPConfig    `yaml:"smtp"`
+	Journald *struct{}      `yaml:"journald"`
+}
+
+type WebhookConfig struct {
+	URL string `yaml:"url"`
+}
+
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+type SMTPConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+}
+
+// loadConfig reads and parses a diskmoose YAML config file.
+func loadConfig(path string) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return Config{}, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// buildNotifiers instantiates the notifiers selected by cfg. With no
+// notifiers configured at all (e.g. no -config flag given), it falls
+// back to the original pts-only behavior so diskmoose keeps working
+// unconfigured.
+func buildNotifiers(cfg Config) []Notifier {
+	n := cfg.Notifiers
+	var notifiers []Notifier
+	if n.Pts != nil {
+		notifiers = append(notifiers, PtsNotifier{})
+	}
+	if n.Wall != nil {
+		notifiers = append(notifiers, WallNotifier{})
+	}
+	if n.Webhook != nil {
+		notifiers = append(notifiers, NewWebhookNotifier(n.Webhook.URL))
+	}
+	if n.Slack != nil {
+		notifiers = append(notifiers, NewSlackNotifier(n.Slack.WebhookURL))
+	}
+	if n.SMTP != nil {
+		s := n.SMTP
+		notifiers = append(notifiers, NewSMTPNotifier(s.Host, s.Port, s.From, s.To, s.Username, s.Password))
+	}
+	if n.Journald != nil {
+		notifiers = append(notifiers, JournaldNotifier{})
+	}
+	if len(notifiers) == 0 {
+		notifiers = append(notifiers, PtsNotifier{})
+	}
+	return notifiers
+}
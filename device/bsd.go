@@ -0,0 +1,71 @@
+//go:build !linux && !windows
+
+package device
+
+import (
+	"log"
+
+	"golang.org/x/sys/unix"
+)
+
+// bsdDevices is the BSD/darwin DevicesInfoGetter, backed by
+// getfsstat(2) and statfs(2), so diskmoose doesn't depend on
+// /usr/bin/mount here either.
+type bsdDevices struct{}
+
+// New returns the DevicesInfoGetter for the current platform.
+// includeFSTypes is ignored here: BSD/darwin has no pseudo-filesystem
+// list to opt back into in the first place.
+func New(includeFSTypes []string) DevicesInfoGetter {
+	return bsdDevices{}
+}
+
+// GetMounts enumerates mounted filesystems via getfsstat(2), the
+// BSD/darwin equivalent of getmntinfo(3).
+func (bsdDevices) GetMounts() ([]Mount, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]unix.Statfs_t, n)
+	if _, err := unix.Getfsstat(buf, unix.MNT_NOWAIT); err != nil {
+		return nil, err
+	}
+	r := make([]Mount, 0, len(buf))
+	for _, stat := range buf {
+		r = append(r, Mount{
+			MountPoint: unix.ByteSliceToString(stat.Mntonname[:]),
+			FSType:     unix.ByteSliceToString(stat.Fstypename[:]),
+			Source:     unix.ByteSliceToString(stat.Mntfromname[:]),
+		})
+	}
+	if len(r) == 0 {
+		return []Mount{{MountPoint: "/"}}, nil
+	}
+	return r, nil
+}
+
+// GetDevicesInfo lists every mount via GetMounts and statfs(2)s each
+// one for its free/total bytes and inodes.
+func (d bsdDevices) GetDevicesInfo() ([]DeviceInfo, error) {
+	mounts, err := d.GetMounts()
+	if err != nil {
+		return nil, err
+	}
+	r := make([]DeviceInfo, 0, len(mounts))
+	for _, m := range mounts {
+		var stat unix.Statfs_t
+		if err := unix.Statfs(m.MountPoint, &stat); err != nil {
+			log.Println("Could not statfs", m.MountPoint)
+			continue
+		}
+		r = append(r, DeviceInfo{
+			Mount:       m,
+			FreeBytes:   uint64(stat.Bavail) * uint64(stat.Bsize),
+			TotalBytes:  uint64(stat.Blocks) * uint64(stat.Bsize),
+			FreeInodes:  uint64(stat.Ffree),
+			TotalInodes: uint64(stat.Files),
+		})
+	}
+	return r, nil
+}
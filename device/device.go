@@ -0,0 +1,35 @@
+// Package device abstracts mount enumeration and capacity reporting
+// across operating systems, so the rest of diskmoose only ever deals
+// with one interface regardless of platform.
+package device
+
+// Mount is a single mounted filesystem, along with the metadata
+// needed to label metrics and alerts: its filesystem type and
+// backing device/source.
+type Mount struct {
+	MountPoint string
+	FSType     string
+	Source     string
+}
+
+// DeviceInfo is a Mount's latest capacity snapshot, in both bytes and
+// inodes. Platforms that don't expose inode counts (e.g. Windows)
+// leave the inode fields at zero.
+type DeviceInfo struct {
+	Mount
+	FreeBytes   uint64
+	TotalBytes  uint64
+	FreeInodes  uint64
+	TotalInodes uint64
+}
+
+// A DevicesInfoGetter enumerates mounted filesystems and reports
+// their capacity. Each OS has its own implementation; New returns the
+// one for the platform diskmoose was built for.
+type DevicesInfoGetter interface {
+	// GetMounts lists every real mount point on the system.
+	GetMounts() ([]Mount, error)
+	// GetDevicesInfo lists every real mount point along with its
+	// current free/total bytes and inodes.
+	GetDevicesInfo() ([]DeviceInfo, error)
+}
@@ -0,0 +1,164 @@
+//go:build linux
+
+package device
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// pseudoFSTypes lists filesystems that never hold user data and are
+// never worth reporting.
+var pseudoFSTypes = map[string]bool{
+	"proc":        true,
+	"sysfs":       true,
+	"cgroup":      true,
+	"cgroup2":     true,
+	"devpts":      true,
+	"devtmpfs":    true,
+	"tmpfs":       true,
+	"overlay":     true,
+	"squashfs":    true,
+	"autofs":      true,
+	"mqueue":      true,
+	"tracefs":     true,
+	"debugfs":     true,
+	"securityfs":  true,
+	"pstore":      true,
+	"bpf":         true,
+	"binfmt_misc": true,
+	"fusectl":     true,
+}
+
+// unescapeOctal turns the \040-style octal escapes that
+// /proc/self/mountinfo uses for spaces and other special characters
+// in paths back into raw bytes.
+func unescapeOctal(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseMountinfoLine tokenizes a single /proc/self/mountinfo line,
+// skipping over the variable-length optional fields in the middle up
+// to the "-" separator, as documented in proc(5).
+func parseMountinfoLine(line string) (mountPoint, fsType, source, root string, major, minor int, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 {
+		return "", "", "", "", 0, 0, false
+	}
+	majorMinor := strings.SplitN(fields[2], ":", 2)
+	if len(majorMinor) != 2 {
+		return "", "", "", "", 0, 0, false
+	}
+	major, _ = strconv.Atoi(majorMinor[0])
+	minor, _ = strconv.Atoi(majorMinor[1])
+	root = unescapeOctal(fields[3])
+	mountPoint = unescapeOctal(fields[4])
+
+	i := 6
+	for i < len(fields) && fields[i] != "-" {
+		i++
+	}
+	if i+2 >= len(fields) {
+		return "", "", "", "", 0, 0, false
+	}
+	fsType = fields[i+1]
+	source = unescapeOctal(fields[i+2])
+	return mountPoint, fsType, source, root, major, minor, true
+}
+
+// linuxDevices is the Linux DevicesInfoGetter, backed by
+// /proc/self/mountinfo and statfs(2).
+type linuxDevices struct {
+	includeFSTypes map[string]bool
+}
+
+// New returns the DevicesInfoGetter for the current platform.
+// includeFSTypes lists filesystem types that should be reported even
+// though they'd otherwise be skipped as pseudo filesystems (Linux
+// only; other platforms have no such list to opt back into, and
+// ignore it).
+func New(includeFSTypes []string) DevicesInfoGetter {
+	include := make(map[string]bool, len(includeFSTypes))
+	for _, t := range includeFSTypes {
+		include[t] = true
+	}
+	return linuxDevices{includeFSTypes: include}
+}
+
+// GetMounts reads /proc/self/mountinfo and returns every real mount
+// point on the system, skipping pseudo filesystems (unless listed in
+// includeFSTypes) and bind-mount duplicates of a mount point already
+// recorded (same device plus the same root inside it).
+func (d linuxDevices) GetMounts() ([]Mount, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := make([]Mount, 0)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		mountPoint, fsType, source, root, major, minor, ok := parseMountinfoLine(scanner.Text())
+		if !ok || (pseudoFSTypes[fsType] && !d.includeFSTypes[fsType]) {
+			continue
+		}
+		key := strconv.Itoa(major) + ":" + strconv.Itoa(minor) + ":" + root
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		r = append(r, Mount{MountPoint: mountPoint, FSType: fsType, Source: source})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return []Mount{{MountPoint: "/"}}, nil
+	}
+	return r, nil
+}
+
+// GetDevicesInfo lists every mount via GetMounts and statfs(2)s each
+// one for its free/total bytes and inodes.
+func (d linuxDevices) GetDevicesInfo() ([]DeviceInfo, error) {
+	mounts, err := d.GetMounts()
+	if err != nil {
+		return nil, err
+	}
+	r := make([]DeviceInfo, 0, len(mounts))
+	for _, m := range mounts {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(m.MountPoint, &stat); err != nil {
+			log.Println("Could not statfs", m.MountPoint)
+			continue
+		}
+		r = append(r, DeviceInfo{
+			Mount:       m,
+			FreeBytes:   stat.Bavail * uint64(stat.Bsize),
+			TotalBytes:  stat.Blocks * uint64(stat.Bsize),
+			FreeInodes:  stat.Ffree,
+			TotalInodes: stat.Files,
+		})
+	}
+	return r, nil
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package device
+
+import "testing"
+
+func TestUnescapeOctal(t *testing.T) {
+	cases := map[string]string{
+		`/mnt/my\040drive`: "/mnt/my drive",
+		"/plain/path":      "/plain/path",
+		`/tab\011here`:     "/tab\there",
+		`/trailing\`:       `/trailing\`,
+	}
+	for in, want := range cases {
+		if got := unescapeOctal(in); got != want {
+			t.Errorf("unescapeOctal(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseMountinfoLine(t *testing.T) {
+	// A representative line from /proc/self/mountinfo, with a root of
+	// "/" and an optional field (shared:1) before the "-" separator.
+	line := `36 35 98:0 / / rw,noatime shared:1 - ext4 /dev/root rw,errors=remount-ro`
+	mountPoint, fsType, source, root, major, minor, ok := parseMountinfoLine(line)
+	if !ok {
+		t.Fatal("expected ok=true")
+	}
+	if mountPoint != "/" || fsType != "ext4" || source != "/dev/root" || root != "/" {
+		t.Errorf("got mountPoint=%q fsType=%q source=%q root=%q", mountPoint, fsType, source, root)
+	}
+	if major != 98 || minor != 0 {
+		t.Errorf("got major:minor = %d:%d, want 98:0", major, minor)
+	}
+
+	// A bind-mounted subdirectory, with an escaped space in the mount
+	// point and no optional fields before "-".
+	line = `40 35 98:0 /home/user/my\040dir /mnt/bind rw - ext4 /dev/root rw`
+	mountPoint, fsType, source, root, major, minor, ok = parseMountinfoLine(line)
+	if !ok {
+		t.Fatal("expected ok=true")
+	}
+	if mountPoint != "/mnt/bind" || root != "/home/user/my dir" {
+		t.Errorf("got mountPoint=%q root=%q", mountPoint, root)
+	}
+	if fsType != "ext4" || source != "/dev/root" || major != 98 || minor != 0 {
+		t.Errorf("got fsType=%q source=%q major:minor=%d:%d", fsType, source, major, minor)
+	}
+
+	if _, _, _, _, _, _, ok := parseMountinfoLine("too short"); ok {
+		t.Error("expected ok=false for a malformed line")
+	}
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package device
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowsDevices is the Windows DevicesInfoGetter, backed by
+// GetLogicalDrives and GetDiskFreeSpaceExW. Windows has no inode
+// concept comparable to POSIX, so DeviceInfo's inode fields are
+// always zero here.
+type windowsDevices struct{}
+
+// New returns the DevicesInfoGetter for the current platform.
+// includeFSTypes is ignored here: Windows enumerates logical drives
+// directly and has no pseudo-filesystem list to opt back into.
+func New(includeFSTypes []string) DevicesInfoGetter {
+	return windowsDevices{}
+}
+
+// GetMounts lists every lettered drive reported by GetLogicalDrives.
+func (windowsDevices) GetMounts() ([]Mount, error) {
+	mask, err := windows.GetLogicalDrives()
+	if err != nil {
+		return nil, err
+	}
+	r := make([]Mount, 0)
+	for i := 0; i < 26; i++ {
+		if mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		root := fmt.Sprintf("%c:\\", 'A'+i)
+		r = append(r, Mount{MountPoint: root, Source: root})
+	}
+	if len(r) == 0 {
+		return []Mount{{MountPoint: `C:\`}}, nil
+	}
+	return r, nil
+}
+
+// GetDevicesInfo lists every drive via GetMounts and queries its
+// free/total bytes with GetDiskFreeSpaceExW.
+func (d windowsDevices) GetDevicesInfo() ([]DeviceInfo, error) {
+	mounts, err := d.GetMounts()
+	if err != nil {
+		return nil, err
+	}
+	r := make([]DeviceInfo, 0, len(mounts))
+	for _, m := range mounts {
+		root, err := windows.UTF16PtrFromString(m.MountPoint)
+		if err != nil {
+			continue
+		}
+		var freeBytes, totalBytes, totalFreeBytes uint64
+		if err := windows.GetDiskFreeSpaceEx(root, &freeBytes, &totalBytes, &totalFreeBytes); err != nil {
+			continue
+		}
+		r = append(r, DeviceInfo{
+			Mount:      m,
+			FreeBytes:  freeBytes,
+			TotalBytes: totalBytes,
+		})
+	}
+	return r, nil
+}
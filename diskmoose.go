@@ -6,25 +6,28 @@ package main
  */
 
 import (
-	"errors"
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
+
+	"github.com/xyproto/diskmoose/device"
 )
 
 const (
-	MIN_MB         = 100
 	CHECK_INTERVAL = 120
 	COWTYPE        = "moose"
 	VERSION        = 0.4
 
-	MOUNTCMD  = "/usr/bin/mount"
 	WHOCMD    = "/usr/bin/who"
-	DFCMD     = "/usr/bin/df"
 	COWSAYCMD = "/usr/bin/cowsay"
 )
 
@@ -36,37 +39,6 @@ const (
    4. Able to use cowsay -f moose and warn users
 */
 
-// Evaluates if the given mount point is relevant for our purposes
-func isRelevant(mountpoint string) bool {
-	switch mountpoint {
-	case "/", "/tmp", "/var", "/var/log", "/var/cache", "/usr", "/home":
-		return true
-	}
-	return false
-}
-
-// Get all relevant mount points by running MOUNTCMD and then parse the output
-func getRelevantMountpoints() []string {
-	r := make([]string, 0)
-	cmd := exec.Command(MOUNTCMD)
-	b, err := cmd.Output()
-	if err != nil {
-		log.Println("Could not run mount")
-		return []string{"/"}
-	}
-	s := string(b)
-	mountpoint := ""
-	for _, line := range strings.Split(s, "\n") {
-		if strings.TrimSpace(line) != "" {
-			mountpoint = getFields(line)[2]
-			if isRelevant(mountpoint) {
-				r = append(r, mountpoint)
-			}
-		}
-	}
-	return r
-}
-
 // Get all pts files we could wish to write to by running WHOCMD
 func getPtsFiles() []string {
 	r := make([]string, 0)
@@ -102,13 +74,6 @@ func writeToPts(pts, msg string) {
 	f.WriteString("\n" + msg + "\n")
 }
 
-// Write a message to all pts devices (excluding screen)
-func writeToAll(msg string) {
-	for _, pts := range getPtsFiles() {
-		writeToPts(pts, msg)
-	}
-}
-
 /* Get the fields of a string
  * "a  b c    d     " gives ["a" "b" "c" "d"]
  * Can be made faster by allocating more space at the start
@@ -126,74 +91,202 @@ func getFields(s string) []string {
 	return r
 }
 
-// Get the number of free MB for a given mountpoint
-func checkFreeSpaceMBytes(mountpoint string) (int, error) {
-	cmd := exec.Command(DFCMD, "-BM", mountpoint)
-	b, err := cmd.Output()
-	if err != nil {
-		log.Println("Could not run df")
-		return 0, err
+// A Threshold is a low-disk-space limit as parsed from the -min flag.
+// It is either a fixed number of free bytes, or a minimum percentage
+// of the filesystem that must remain free.
+type Threshold struct {
+	bytes   uint64
+	percent float64
+}
+
+// parseThreshold parses a -min flag value such as "100MB", "2GB" or
+// "10%" into a Threshold.
+func parseThreshold(s string) (Threshold, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return Threshold{}, fmt.Errorf("invalid percentage threshold %q: %w", s, err)
+		}
+		return Threshold{percent: percent}, nil
 	}
-	s := string(b)
-	// Get the fields from the second line, not the headline
-	fields := getFields(strings.Split(s, "\n")[1])
-	if len(fields) < 5 {
-		log.Println("Too little output from df")
-		return 0, errors.New("Too little output from df")
-	}
-	df_mountpoint := fields[5]
-	if df_mountpoint != mountpoint {
-		log.Println("df could not check the given mountpoint: mismatch")
-		log.Println("mountpoint from df:")
-		log.Println(df_mountpoint)
-		log.Println("mountpoint from diskmoose:")
-		log.Println(mountpoint)
-		return 0, errors.New("df could not check the given mountpoint")
-	}
-	sMBfree := fields[3]
-	if strings.Index(sMBfree, "M") == -1 {
-		log.Println("No \"M\" in output from df")
-		return 0, errors.New("No \"M\" in output from df")
-	}
-	mbfree, err := strconv.Atoi(strings.Split(sMBfree, "M")[0])
+	var multiplier uint64
+	switch {
+	case strings.HasSuffix(s, "GB"):
+		multiplier = 1 << 30
+		s = strings.TrimSuffix(s, "GB")
+	case strings.HasSuffix(s, "MB"):
+		multiplier = 1 << 20
+		s = strings.TrimSuffix(s, "MB")
+	default:
+		return Threshold{}, fmt.Errorf("threshold %q must end in MB, GB or %%", s)
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
 	if err != nil {
-		log.Println("Could not get MB free number from df")
-		return 0, err
+		return Threshold{}, fmt.Errorf("invalid threshold %q: %w", s, err)
+	}
+	return Threshold{bytes: n * multiplier}, nil
+}
+
+// exceeded reports whether the given free/total byte counts have
+// dropped below the threshold.
+func (t Threshold) exceeded(freeBytes, totalBytes uint64) bool {
+	if t.percent > 0 {
+		if totalBytes == 0 {
+			return false
+		}
+		return (float64(freeBytes)/float64(totalBytes))*100 < t.percent
 	}
-	return mbfree, nil
+	return freeBytes < t.bytes
 }
 
-// Uses cowsay to make a moose say the given message
+// half returns a Threshold at half the limit, used to derive a
+// critical level from a warning-level Threshold.
+func (t Threshold) half() Threshold {
+	if t.percent > 0 {
+		return Threshold{percent: t.percent / 2}
+	}
+	return Threshold{bytes: t.bytes / 2}
+}
+
+// Uses cowsay to make a moose say the given message, falling back to
+// an embedded pure-Go renderer when COWSAYCMD isn't installed, so
+// diskmoose keeps talking on a box without cowsay.
 func mooseSays(msg string) string {
+	if _, err := exec.LookPath(COWSAYCMD); err != nil {
+		return mooseSaysEmbedded(msg)
+	}
 	cmd := exec.Command(COWSAYCMD, "-f", COWTYPE, msg)
 	b, err := cmd.Output()
 	if err != nil {
 		log.Println("Could not run cowsay")
-		return msg
+		return mooseSaysEmbedded(msg)
 	}
 	return string(b)
 }
 
 func main() {
-	var (
-		freeMBytes int
-		msg        string
-		err        error
-	)
-	msg = fmt.Sprintf("I'll let you know if there are less than %v MB free in /, /tmp, /var, /var/log, /var/cache, /usr or /home. Just let me run in the background.", MIN_MB)
+	minFlag := flag.String("min", "100MB", "low disk space threshold (e.g. 100MB, 2GB or 10%)")
+	configFlag := flag.String("config", "", "path to a notifiers config file (YAML)")
+	listenFlag := flag.String("listen", ":9112", "address for the /metrics, /healthz and /alert HTTP endpoints (empty to disable)")
+	stateDirFlag := flag.String("state-dir", "/var/lib/diskmoose", "directory for persisted usage history used by time-to-full predictions")
+	horizonFlag := flag.Duration("horizon", defaultHorizon, "only predict time-to-full alerts within this horizon")
+	minRSquaredFlag := flag.Float64("min-rsquared", defaultMinRSquared, "minimum R² (goodness of fit) required to act on a time-to-full prediction")
+	flag.Parse()
+
+	var cfg Config
+	if *configFlag != "" {
+		var err error
+		cfg, err = loadConfig(*configFlag)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	notifiers := buildNotifiers(cfg)
+
+	// -min takes precedence when given explicitly on the command
+	// line; otherwise a configured cfg.Min wins over -min's default.
+	minSetExplicitly := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "min" {
+			minSetExplicitly = true
+		}
+	})
+	minStr := *minFlag
+	if cfg.Min != "" && !minSetExplicitly {
+		minStr = cfg.Min
+	}
+	threshold, err := parseThreshold(minStr)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	hostname, _ := os.Hostname()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var srv *http.Server
+	if *listenFlag != "" {
+		srv = startServer(*listenFlag, notifiers)
+	}
+
+	states := make(map[string]*mountState)
+	predictStates := make(map[string]*predictiveState)
+	devices := device.New(cfg.IncludeFSTypes)
+
+	watchedMounts := cfg.Mounts
+	if len(watchedMounts) == 0 {
+		watchedMounts = defaultMountPatterns
+	}
+	msg := fmt.Sprintf("I'll let you know if there's less than %s free in %s. Just let me run in the background.", minStr, strings.Join(watchedMounts, ", "))
 	fmt.Println(mooseSays(msg))
+pollLoop:
 	for {
-		for _, mountpoint := range getRelevantMountpoints() {
-			freeMBytes, err = checkFreeSpaceMBytes(mountpoint)
-			if err != nil {
-				log.Printf("Could not get free space for %s.\nAborting.", mountpoint)
-				os.Exit(1)
+		infos, err := devices.GetDevicesInfo()
+		if err != nil {
+			log.Println("Could not get device info:", err)
+		}
+		for _, info := range selectMounts(infos, cfg.Mounts) {
+			recordMountMetrics(info)
+			now := time.Now()
+
+			if history, ok := recordAndPredict(*stateDirFlag, info, now); ok {
+				if remaining, predicted := predictTimeToFull(history, now, *minRSquaredFlag); predicted && remaining < *horizonFlag {
+					if notifyPredictive(predictStates, info.MountPoint, now) {
+						alert := Alert{
+							Mountpoint: info.MountPoint,
+							FreeBytes:  info.FreeBytes,
+							TotalBytes: info.TotalBytes,
+							Percent:    percentFree(info.FreeBytes, info.TotalBytes),
+							Hostname:   hostname,
+							Severity:   SeverityWarning,
+							Message:    fmt.Sprintf("moose predicts %s full in ~%s at current growth", info.MountPoint, roughDuration(remaining)),
+						}
+						for _, notifier := range notifiers {
+							if err := notifier.Notify(ctx, alert); err != nil {
+								log.Println("notifier failed:", err)
+							}
+						}
+					}
+				} else {
+					delete(predictStates, info.MountPoint)
+				}
 			}
-			if freeMBytes < MIN_MB { //freeMBytes > 0
-				msg = fmt.Sprintf("Only %v MB free on %v", freeMBytes, mountpoint)
-				writeToAll(mooseSays(msg))
+
+			mountThreshold, minInodes := thresholdFor(info.MountPoint, threshold, cfg.Thresholds)
+			level := classify(info, mountThreshold, minInodes)
+
+			state, ok := states[info.MountPoint]
+			if !ok {
+				state = &mountState{}
+				states[info.MountPoint] = state
 			}
+			notify, cleared := state.update(now, level)
+			if !notify {
+				continue
+			}
+			if !cleared {
+				recordAlertFired(info.Mount)
+			}
+			alert := buildAlert(info, level, cleared, minInodes, hostname)
+			for _, notifier := range notifiers {
+				if err := notifier.Notify(ctx, alert); err != nil {
+					log.Println("notifier failed:", err)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			break pollLoop
+		case <-time.After(CHECK_INTERVAL * time.Second):
+		}
+	}
+
+	if srv != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Println("HTTP server shutdown:", err)
 		}
-		time.Sleep(CHECK_INTERVAL * 1e9)
 	}
 }
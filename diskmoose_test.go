@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestParseThreshold(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    Threshold
+		wantErr bool
+	}{
+		{in: "100MB", want: Threshold{bytes: 100 << 20}},
+		{in: "2GB", want: Threshold{bytes: 2 << 30}},
+		{in: "10%", want: Threshold{percent: 10}},
+		{in: " 50MB ", want: Threshold{bytes: 50 << 20}},
+		{in: "100KB", wantErr: true},
+		{in: "nope", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := parseThreshold(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseThreshold(%q): expected error, got %+v", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseThreshold(%q): unexpected error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("parseThreshold(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestThresholdExceeded(t *testing.T) {
+	byBytes := Threshold{bytes: 100 << 20}
+	if !byBytes.exceeded(50<<20, 1<<30) {
+		t.Error("expected byte threshold to be exceeded with 50MB free")
+	}
+	if byBytes.exceeded(200<<20, 1<<30) {
+		t.Error("expected byte threshold not to be exceeded with 200MB free")
+	}
+
+	byPercent := Threshold{percent: 10}
+	if !byPercent.exceeded(5, 100) {
+		t.Error("expected percent threshold to be exceeded at 5% free")
+	}
+	if byPercent.exceeded(50, 100) {
+		t.Error("expected percent threshold not to be exceeded at 50% free")
+	}
+	if byPercent.exceeded(0, 0) {
+		t.Error("expected percent threshold not to be exceeded when total is 0")
+	}
+}
+
+func TestThresholdHalf(t *testing.T) {
+	if got := (Threshold{bytes: 100}).half(); got.bytes != 50 {
+		t.Errorf("half of 100 bytes = %d, want 50", got.bytes)
+	}
+	if got := (Threshold{percent: 10}).half(); got.percent != 5 {
+		t.Errorf("half of 10%% = %v, want 5", got.percent)
+	}
+}
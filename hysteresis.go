@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xyproto/diskmoose/device"
+)
+
+// alertLevel classifies how severe a mount's disk or inode pressure
+// currently is.
+type alertLevel int
+
+const (
+	levelOK alertLevel = iota
+	levelWarning
+	levelCritical
+)
+
+func (l alertLevel) String() string {
+	switch l {
+	case levelCritical:
+		return SeverityCritical
+	case levelWarning:
+		return SeverityWarning
+	default:
+		return "ok"
+	}
+}
+
+const (
+	initialRenotifyInterval = CHECK_INTERVAL * time.Second
+	maxRenotifyInterval     = 24 * time.Hour
+)
+
+// mountState tracks the hysteresis and rate-limiting state for a
+// single mountpoint across polls.
+type mountState struct {
+	level      alertLevel
+	lastNotify time.Time
+	backoff    time.Duration
+}
+
+// classify decides the alertLevel for a poll result against a
+// Threshold and a minimum-free-inodes floor (0 disables the inode
+// check). Crossing below half the configured threshold is treated as
+// critical.
+func classify(info device.DeviceInfo, threshold Threshold, minInodes uint64) alertLevel {
+	critical := threshold.half().exceeded(info.FreeBytes, info.TotalBytes) ||
+		(minInodes > 0 && info.FreeInodes < minInodes/2)
+	warning := threshold.exceeded(info.FreeBytes, info.TotalBytes) ||
+		(minInodes > 0 && info.FreeInodes < minInodes)
+	switch {
+	case critical:
+		return levelCritical
+	case warning:
+		return levelWarning
+	default:
+		return levelOK
+	}
+}
+
+// update advances the mount's state machine to newLevel and reports
+// whether a notification should be sent now, and whether this is a
+// transition back to OK (a "clear" notification).
+//
+// A level change always notifies immediately and resets the backoff.
+// Staying at the same non-OK level only re-notifies once the backoff
+// has elapsed, doubling it (up to maxRenotifyInterval) each time, so
+// a stuck alert tapers off instead of repeating every poll forever.
+func (s *mountState) update(now time.Time, newLevel alertLevel) (notify, cleared bool) {
+	if newLevel == levelOK {
+		cleared = s.level != levelOK
+		s.level = levelOK
+		s.backoff = 0
+		return cleared, cleared
+	}
+	if newLevel != s.level {
+		s.level = newLevel
+		s.backoff = initialRenotifyInterval
+		s.lastNotify = now
+		return true, false
+	}
+	if now.Sub(s.lastNotify) >= s.backoff {
+		s.lastNotify = now
+		s.backoff *= 2
+		if s.backoff > maxRenotifyInterval {
+			s.backoff = maxRenotifyInterval
+		}
+		return true, false
+	}
+	return false, false
+}
+
+// buildAlert formats the Alert to send for a device given its latest
+// DeviceInfo and state-machine decision.
+func buildAlert(info device.DeviceInfo, level alertLevel, cleared bool, minInodes uint64, hostname string) Alert {
+	inodePressure := minInodes > 0 && info.FreeInodes < minInodes
+
+	var msg string
+	severity := level.String()
+	switch {
+	case cleared:
+		severity = "ok"
+		msg = fmt.Sprintf("%s is back to OK: %d MB free", info.MountPoint, info.FreeBytes/(1<<20))
+	case inodePressure && level == levelCritical:
+		msg = fmt.Sprintf("Critical: only %d inodes free on %s", info.FreeInodes, info.MountPoint)
+	case inodePressure:
+		msg = fmt.Sprintf("Only %d inodes free on %s", info.FreeInodes, info.MountPoint)
+	case level == levelCritical:
+		msg = fmt.Sprintf("Critical: only %d MB free on %s", info.FreeBytes/(1<<20), info.MountPoint)
+	default:
+		msg = fmt.Sprintf("Only %d MB free on %s", info.FreeBytes/(1<<20), info.MountPoint)
+	}
+
+	return Alert{
+		Mountpoint: info.MountPoint,
+		FreeBytes:  info.FreeBytes,
+		TotalBytes: info.TotalBytes,
+		Percent:    percentFree(info.FreeBytes, info.TotalBytes),
+		Hostname:   hostname,
+		Severity:   severity,
+		Message:    msg,
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyproto/diskmoose/device"
+)
+
+func TestClassify(t *testing.T) {
+	threshold := Threshold{percent: 10}
+
+	ok := classify(device.DeviceInfo{FreeBytes: 50, TotalBytes: 100}, threshold, 0)
+	if ok != levelOK {
+		t.Errorf("classify(50%% free) = %v, want levelOK", ok)
+	}
+
+	warning := classify(device.DeviceInfo{FreeBytes: 8, TotalBytes: 100}, threshold, 0)
+	if warning != levelWarning {
+		t.Errorf("classify(8%% free) = %v, want levelWarning", warning)
+	}
+
+	critical := classify(device.DeviceInfo{FreeBytes: 4, TotalBytes: 100}, threshold, 0)
+	if critical != levelCritical {
+		t.Errorf("classify(4%% free, below half threshold) = %v, want levelCritical", critical)
+	}
+
+	// Inode pressure is independent of the byte threshold.
+	inodeWarning := classify(device.DeviceInfo{FreeBytes: 50, TotalBytes: 100, FreeInodes: 500}, threshold, 1000)
+	if inodeWarning != levelWarning {
+		t.Errorf("classify(free inodes below floor) = %v, want levelWarning", inodeWarning)
+	}
+	inodeCritical := classify(device.DeviceInfo{FreeBytes: 50, TotalBytes: 100, FreeInodes: 400}, threshold, 1000)
+	if inodeCritical != levelCritical {
+		t.Errorf("classify(free inodes below half floor) = %v, want levelCritical", inodeCritical)
+	}
+
+	// minInodes == 0 disables the inode check entirely.
+	if got := classify(device.DeviceInfo{FreeBytes: 50, TotalBytes: 100, FreeInodes: 0}, threshold, 0); got != levelOK {
+		t.Errorf("classify(minInodes=0) = %v, want levelOK", got)
+	}
+}
+
+func TestMountStateUpdate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("level change notifies and resets backoff", func(t *testing.T) {
+		s := &mountState{}
+		notify, cleared := s.update(now, levelWarning)
+		if !notify || cleared {
+			t.Fatalf("update(OK->warning) = (%v, %v), want (true, false)", notify, cleared)
+		}
+		if s.backoff != initialRenotifyInterval {
+			t.Errorf("backoff = %v, want %v", s.backoff, initialRenotifyInterval)
+		}
+	})
+
+	t.Run("same level suppresses until backoff elapses", func(t *testing.T) {
+		s := &mountState{}
+		s.update(now, levelWarning)
+
+		// Too soon: no re-notify.
+		notify, cleared := s.update(now.Add(initialRenotifyInterval/2), levelWarning)
+		if notify || cleared {
+			t.Fatalf("update before backoff elapsed = (%v, %v), want (false, false)", notify, cleared)
+		}
+
+		// Backoff elapsed: re-notify and double the backoff.
+		notify, cleared = s.update(now.Add(initialRenotifyInterval), levelWarning)
+		if !notify || cleared {
+			t.Fatalf("update after backoff elapsed = (%v, %v), want (true, false)", notify, cleared)
+		}
+		if s.backoff != initialRenotifyInterval*2 {
+			t.Errorf("backoff = %v, want %v", s.backoff, initialRenotifyInterval*2)
+		}
+	})
+
+	t.Run("backoff doubling is capped at maxRenotifyInterval", func(t *testing.T) {
+		s := &mountState{level: levelWarning, backoff: maxRenotifyInterval, lastNotify: now}
+		notify, cleared := s.update(now.Add(maxRenotifyInterval), levelWarning)
+		if !notify || cleared {
+			t.Fatalf("update at cap = (%v, %v), want (true, false)", notify, cleared)
+		}
+		if s.backoff != maxRenotifyInterval {
+			t.Errorf("backoff = %v, want capped at %v", s.backoff, maxRenotifyInterval)
+		}
+	})
+
+	t.Run("level escalation re-notifies immediately", func(t *testing.T) {
+		s := &mountState{}
+		s.update(now, levelWarning)
+		notify, cleared := s.update(now.Add(time.Second), levelCritical)
+		if !notify || cleared {
+			t.Fatalf("update(warning->critical) = (%v, %v), want (true, false)", notify, cleared)
+		}
+		if s.level != levelCritical {
+			t.Errorf("level = %v, want levelCritical", s.level)
+		}
+	})
+
+	t.Run("transition back to OK clears and resets backoff", func(t *testing.T) {
+		s := &mountState{}
+		s.update(now, levelCritical)
+		notify, cleared := s.update(now.Add(time.Second), levelOK)
+		if !notify || !cleared {
+			t.Fatalf("update(critical->OK) = (%v, %v), want (true, true)", notify, cleared)
+		}
+		if s.level != levelOK || s.backoff != 0 {
+			t.Errorf("state after clear = %+v, want level=levelOK backoff=0", s)
+		}
+
+		// Staying at OK never re-notifies.
+		notify, cleared = s.update(now.Add(2*time.Second), levelOK)
+		if notify || cleared {
+			t.Errorf("update(OK->OK) = (%v, %v), want (false, false)", notify, cleared)
+		}
+	})
+}
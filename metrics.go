@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xyproto/diskmoose/device"
+)
+
+var (
+	freeBytesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "diskmoose_filesystem_free_bytes",
+		Help: "Free bytes on a watched filesystem.",
+	}, []string{"mountpoint", "fstype", "device"})
+
+	sizeBytesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "diskmoose_filesystem_size_bytes",
+		Help: "Total size in bytes of a watched filesystem.",
+	}, []string{"mountpoint", "fstype", "device"})
+
+	usedPercentGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "diskmoose_filesystem_used_percent",
+		Help: "Percentage of a watched filesystem currently in use.",
+	}, []string{"mountpoint", "fstype", "device"})
+
+	alertsFiredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "diskmoose_alerts_fired_total",
+		Help: "Number of low-disk-space alerts fired for a filesystem.",
+	}, []string{"mountpoint", "fstype", "device"})
+)
+
+func init() {
+	prometheus.MustRegister(freeBytesGauge, sizeBytesGauge, usedPercentGauge, alertsFiredTotal)
+}
+
+// recordMountMetrics updates the gauges for a single device after a
+// poll.
+func recordMountMetrics(info device.DeviceInfo) {
+	freeBytesGauge.WithLabelValues(info.MountPoint, info.FSType, info.Source).Set(float64(info.FreeBytes))
+	sizeBytesGauge.WithLabelValues(info.MountPoint, info.FSType, info.Source).Set(float64(info.TotalBytes))
+	usedPercentGauge.WithLabelValues(info.MountPoint, info.FSType, info.Source).Set(100 - percentFree(info.FreeBytes, info.TotalBytes))
+}
+
+// recordAlertFired increments the fired-alerts counter for a mount.
+func recordAlertFired(mount device.Mount) {
+	alertsFiredTotal.WithLabelValues(mount.MountPoint, mount.FSType, mount.Source).Inc()
+}
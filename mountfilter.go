@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/xyproto/diskmoose/device"
+)
+
+// defaultMountPatterns is used when no mount patterns are configured,
+// preserving diskmoose's original hardcoded set of watched
+// mountpoints.
+var defaultMountPatterns = []string{"/", "/tmp", "/var", "/var/log", "/var/cache", "/usr", "/home"}
+
+// isRelevant reports whether mountpoint matches one of patterns,
+// interpreted as filepath.Match globs (so "/var/*" covers every
+// mount directly under /var).
+func isRelevant(mountpoint string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(pattern, mountpoint); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// selectMounts filters devices down to the ones matching patterns,
+// falling back to defaultMountPatterns when patterns is empty.
+func selectMounts(devices []device.DeviceInfo, patterns []string) []device.DeviceInfo {
+	if len(patterns) == 0 {
+		patterns = defaultMountPatterns
+	}
+	r := make([]device.DeviceInfo, 0, len(devices))
+	for _, d := range devices {
+		if isRelevant(d.MountPoint, patterns) {
+			r = append(r, d)
+		}
+	}
+	return r
+}
+
+// thresholdFor returns the Threshold and minimum-free-inodes floor to
+// use for mountpoint: the first matching entry in overrides, falling
+// back to def (with no inode floor) when nothing matches.
+func thresholdFor(mountpoint string, def Threshold, overrides []MountThreshold) (Threshold, uint64) {
+	for _, o := range overrides {
+		ok, err := filepath.Match(o.Pattern, mountpoint)
+		if err != nil || !ok {
+			continue
+		}
+		threshold := def
+		if o.Min != "" {
+			if parsed, err := parseThreshold(o.Min); err == nil {
+				threshold = parsed
+			}
+		}
+		return threshold, o.Inodes
+	}
+	return def, 0
+}
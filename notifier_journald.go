@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const journaldSocket = "/run/systemd/journal/socket"
+
+// JournaldNotifier writes structured fields to the systemd-journal
+// socket, the same mechanism sd_journal_send(3) uses, so alerts show
+// up in `journalctl` with MOUNTPOINT= and related fields attached.
+type JournaldNotifier struct{}
+
+func (JournaldNotifier) Notify(ctx context.Context, alert Alert) error {
+	conn, err := net.Dial("unixgram", journaldSocket)
+	if err != nil {
+		return fmt.Errorf("could not reach systemd-journal socket: %w", err)
+	}
+	defer conn.Close()
+
+	priority := "4" // warning
+	if alert.Severity == SeverityCritical {
+		priority = "2" // critical
+	}
+
+	var buf bytes.Buffer
+	appendJournalField(&buf, "MESSAGE", alert.Message)
+	appendJournalField(&buf, "PRIORITY", priority)
+	appendJournalField(&buf, "SYSLOG_IDENTIFIER", "diskmoose")
+	appendJournalField(&buf, "MOUNTPOINT", alert.Mountpoint)
+	appendJournalField(&buf, "FREE_BYTES", strconv.FormatUint(alert.FreeBytes, 10))
+	appendJournalField(&buf, "TOTAL_BYTES", strconv.FormatUint(alert.TotalBytes, 10))
+
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
+
+// appendJournalField appends one field to buf using the systemd
+// native journal protocol (see sd_journal_send(3) and
+// systemd.journal-fields(7)): NAME=value\n for values that are
+// newline-free, or the binary-safe NAME\n<8-byte LE length>value\n
+// form otherwise. value is attacker-controlled for some fields (e.g.
+// Alert.Message can come from diskmoose's unauthenticated /alert
+// endpoint), so a literal newline must not be allowed to break the
+// datagram framing or forge additional fields.
+func appendJournalField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,15 @@
+package main
+
+import "context"
+
+// PtsNotifier delivers alerts by writing a cowsay'd message directly
+// to every pts device, the original diskmoose behavior.
+type PtsNotifier struct{}
+
+func (PtsNotifier) Notify(ctx context.Context, alert Alert) error {
+	msg := mooseSays(alert.Message)
+	for _, pts := range getPtsFiles() {
+		writeToPts(pts, msg)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// notifierHTTPTimeout bounds how long a single Slack/webhook POST may
+// take, so an unresponsive endpoint can't wedge the poll loop forever.
+const notifierHTTPTimeout = 10 * time.Second
+
+// SlackNotifier posts alerts to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// NewSlackNotifier returns a SlackNotifier posting to the given
+// incoming webhook URL, using a client with a bounded timeout.
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{WebhookURL: webhookURL, Client: &http.Client{Timeout: notifierHTTPTimeout}}
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(slackPayload{Text: ":moose: " + alert.Message})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpDialTimeout bounds how long connecting to and talking with the
+// relay may take, so an unreachable/firewalled host can't wedge the
+// poll loop forever.
+const smtpDialTimeout = 10 * time.Second
+
+// SMTPNotifier emails alerts through an SMTP relay.
+type SMTPNotifier struct {
+	Host string
+	Port int
+	From string
+	To   []string
+	Auth smtp.Auth
+}
+
+// NewSMTPNotifier returns an SMTPNotifier. username is left empty to
+// skip authentication (e.g. for a local relay).
+func NewSMTPNotifier(host string, port int, from string, to []string, username, password string) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPNotifier{Host: host, Port: port, From: from, To: to, Auth: auth}
+}
+
+func (s *SMTPNotifier) Notify(ctx context.Context, alert Alert) error {
+	addr := fmt.Sprintf("%s:%d", s.Host, s.Port)
+	subject := fmt.Sprintf("diskmoose: %s on %s", alert.Severity, alert.Mountpoint)
+	body := fmt.Sprintf("To: %s\r\nFrom: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		strings.Join(s.To, ", "), s.From, subject, alert.Message)
+
+	conn, err := net.DialTimeout("tcp", addr, smtpDialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Abort the conversation as soon as the caller's context is done,
+	// whichever comes first.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	deadline := time.Now().Add(smtpDialTimeout)
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.Auth != nil {
+		if err := client.Auth(s.Auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,16 @@
+package main
+
+import "context"
+
+// WallNotifier behaves like wall(1): it broadcasts a cowsay'd message
+// to every tty with an active login session, discovered from the
+// kernel's utmp session database instead of by scraping `who` output.
+type WallNotifier struct{}
+
+func (WallNotifier) Notify(ctx context.Context, alert Alert) error {
+	msg := mooseSays(alert.Message)
+	for _, tty := range getWallTTYs() {
+		writeToPts(tty, msg)
+	}
+	return nil
+}
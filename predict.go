@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xyproto/diskmoose/device"
+)
+
+// sample is one (timestamp, used bytes) observation persisted for a
+// mountpoint's growth history.
+type sample struct {
+	Time time.Time `json:"t"`
+	Used uint64    `json:"u"`
+}
+
+// usageHistory is the bounded ring buffer of samples for one
+// mountpoint, plus the filesystem capacity it was last observed at so
+// a resize (capacity change) can be detected and the history reset.
+type usageHistory struct {
+	Capacity uint64   `json:"capacity"`
+	Samples  []sample `json:"samples"`
+}
+
+const (
+	maxSamples         = 200
+	regressionWindow   = 20
+	defaultMinRSquared = 0.5
+	predictiveResend   = 6 * time.Hour
+	defaultHorizon     = 72 * time.Hour
+)
+
+// historyPath returns the on-disk path for a mountpoint's usage
+// history file under dir.
+func historyPath(dir, mountpoint string) string {
+	name := strings.ReplaceAll(strings.Trim(mountpoint, "/"), "/", "_")
+	if name == "" {
+		name = "root"
+	}
+	return filepath.Join(dir, name+".json")
+}
+
+// loadHistory reads a mountpoint's usage history, returning an empty
+// one if it doesn't exist yet or can't be parsed.
+func loadHistory(dir, mountpoint string) usageHistory {
+	b, err := os.ReadFile(historyPath(dir, mountpoint))
+	if err != nil {
+		return usageHistory{}
+	}
+	var h usageHistory
+	if err := json.Unmarshal(b, &h); err != nil {
+		return usageHistory{}
+	}
+	return h
+}
+
+// saveHistory persists h for mountpoint under dir, creating dir if
+// necessary.
+func saveHistory(dir, mountpoint string, h usageHistory) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(historyPath(dir, mountpoint), b, 0644)
+}
+
+// recordUsage appends a (now, used) sample to h, capped at
+// maxSamples, resetting the history first if capacity changed since
+// the last observation (i.e. the filesystem was resized).
+func recordUsage(h usageHistory, now time.Time, used, capacity uint64) usageHistory {
+	if h.Capacity != 0 && h.Capacity != capacity {
+		h = usageHistory{}
+	}
+	h.Capacity = capacity
+	h.Samples = append(h.Samples, sample{Time: now, Used: used})
+	if len(h.Samples) > maxSamples {
+		h.Samples = h.Samples[len(h.Samples)-maxSamples:]
+	}
+	return h
+}
+
+// fitLine fits used = a*t + b by least squares over samples, with t
+// measured in seconds since the first sample, and returns the slope,
+// intercept and R² (coefficient of determination) of the fit.
+func fitLine(samples []sample) (slope, intercept, rSquared float64) {
+	n := float64(len(samples))
+	if n < 2 {
+		return 0, 0, 0
+	}
+	t0 := samples[0].Time
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.Time.Sub(t0).Seconds()
+		y := float64(s.Used)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, 0, 0
+	}
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+
+	meanY := sumY / n
+	var ssTot, ssRes float64
+	for _, s := range samples {
+		x := s.Time.Sub(t0).Seconds()
+		y := float64(s.Used)
+		predicted := slope*x + intercept
+		ssRes += (y - predicted) * (y - predicted)
+		ssTot += (y - meanY) * (y - meanY)
+	}
+	if ssTot == 0 {
+		return slope, intercept, 1
+	}
+	return slope, intercept, 1 - ssRes/ssTot
+}
+
+// predictTimeToFull fits a growth trend over h's most recent samples
+// and, if the fit is confident (R² >= minRSquared) and usage is
+// growing, returns the estimated time remaining until the mount is
+// full. ok is false when there isn't enough data yet, or the trend
+// isn't both positive and confident enough to act on.
+func predictTimeToFull(h usageHistory, now time.Time, minRSquared float64) (remaining time.Duration, ok bool) {
+	samples := h.Samples
+	if len(samples) > regressionWindow {
+		samples = samples[len(samples)-regressionWindow:]
+	}
+	if len(samples) < 2 || h.Capacity == 0 {
+		return 0, false
+	}
+	slope, intercept, rSquared := fitLine(samples)
+	if slope <= 0 || rSquared < minRSquared {
+		return 0, false
+	}
+	t0 := samples[0].Time
+	usedNow := slope*now.Sub(t0).Seconds() + intercept
+	bytesLeft := float64(h.Capacity) - usedNow
+	if bytesLeft <= 0 {
+		return 0, true
+	}
+	return time.Duration(bytesLeft / slope * float64(time.Second)), true
+}
+
+// recordAndPredict loads info's persisted usage history, appends the
+// latest observation, saves it back under dir, and returns the
+// updated history. ok is false if the history could not be saved,
+// since a prediction from an unsaved history would just be repeated
+// (and re-fit from scratch) on every poll.
+func recordAndPredict(dir string, info device.DeviceInfo, now time.Time) (usageHistory, bool) {
+	history := loadHistory(dir, info.MountPoint)
+	history = recordUsage(history, now, info.TotalBytes-info.FreeBytes, info.TotalBytes)
+	if err := saveHistory(dir, info.MountPoint, history); err != nil {
+		return usageHistory{}, false
+	}
+	return history, true
+}
+
+// predictiveState rate-limits how often a time-to-full prediction is
+// re-announced for a mountpoint while it keeps predicting a breach.
+type predictiveState struct {
+	lastNotify time.Time
+}
+
+// notifyPredictive reports whether a predictive alert for mountpoint
+// should fire now, given the per-mountpoint resend interval.
+func notifyPredictive(states map[string]*predictiveState, mountpoint string, now time.Time) bool {
+	state, ok := states[mountpoint]
+	if !ok {
+		state = &predictiveState{}
+		states[mountpoint] = state
+	}
+	if now.Sub(state.lastNotify) < predictiveResend {
+		return false
+	}
+	state.lastNotify = now
+	return true
+}
+
+// roughDuration formats d the way the moose talks about it: minutes
+// while short, hours up to two days, days beyond that.
+func roughDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
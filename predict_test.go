@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// linearSamples builds n hourly samples starting at used bytes start
+// and growing by bytesPerHour each hour, an exact fit for fitLine.
+func linearSamples(start time.Time, n int, startUsed, bytesPerHour uint64) []sample {
+	samples := make([]sample, n)
+	for i := 0; i < n; i++ {
+		samples[i] = sample{
+			Time: start.Add(time.Duration(i) * time.Hour),
+			Used: startUsed + uint64(i)*bytesPerHour,
+		}
+	}
+	return samples
+}
+
+func TestFitLine(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := linearSamples(start, 10, 1000, 100)
+
+	slope, intercept, rSquared := fitLine(samples)
+	wantSlope := 100.0 / 3600 // 100 bytes/hour in bytes/second
+	if math.Abs(slope-wantSlope) > 1e-9 {
+		t.Errorf("slope = %v, want %v", slope, wantSlope)
+	}
+	if math.Abs(intercept-1000) > 1e-6 {
+		t.Errorf("intercept = %v, want 1000", intercept)
+	}
+	if math.Abs(rSquared-1) > 1e-9 {
+		t.Errorf("rSquared = %v, want 1 for an exact linear fit", rSquared)
+	}
+
+	if slope, intercept, rSquared := fitLine(samples[:1]); slope != 0 || intercept != 0 || rSquared != 0 {
+		t.Errorf("fitLine with <2 samples = (%v, %v, %v), want all zero", slope, intercept, rSquared)
+	}
+}
+
+func TestPredictTimeToFull(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 10 hourly samples growing 100 bytes/hour from 1000, capacity
+	// 2500: at hour 9 used=1900, so (2500-1900)/100 = 6h remaining.
+	h := usageHistory{
+		Capacity: 2500,
+		Samples:  linearSamples(start, 10, 1000, 100),
+	}
+	now := start.Add(9 * time.Hour)
+
+	remaining, ok := predictTimeToFull(h, now, defaultMinRSquared)
+	if !ok {
+		t.Fatal("expected a confident prediction for an exact linear fit")
+	}
+	if remaining != 6*time.Hour {
+		t.Errorf("remaining = %v, want 6h0m0s", remaining)
+	}
+
+	// Not enough samples yet.
+	if _, ok := predictTimeToFull(usageHistory{Capacity: 2500, Samples: h.Samples[:1]}, now, defaultMinRSquared); ok {
+		t.Error("expected ok=false with fewer than 2 samples")
+	}
+
+	// Flat usage never reaches the horizon.
+	flat := usageHistory{Capacity: 2500, Samples: linearSamples(start, 10, 1000, 0)}
+	if _, ok := predictTimeToFull(flat, now, defaultMinRSquared); ok {
+		t.Error("expected ok=false for a non-growing trend")
+	}
+
+	// Usage that has already exceeded capacity predicts zero
+	// remaining time rather than a negative duration.
+	full := usageHistory{Capacity: 1500, Samples: linearSamples(start, 10, 1000, 100)}
+	remaining, ok = predictTimeToFull(full, now, defaultMinRSquared)
+	if !ok || remaining != 0 {
+		t.Errorf("remaining = %v, ok = %v, want 0, true for an already-exceeded capacity", remaining, ok)
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := recordUsage(usageHistory{}, now, 100, 1000)
+	if h.Capacity != 1000 || len(h.Samples) != 1 {
+		t.Fatalf("got %+v", h)
+	}
+
+	// A capacity change (resize) resets the history.
+	h = recordUsage(h, now.Add(time.Hour), 200, 2000)
+	if h.Capacity != 2000 || len(h.Samples) != 1 {
+		t.Fatalf("expected history reset on capacity change, got %+v", h)
+	}
+
+	// Samples are capped at maxSamples.
+	for i := 0; i < maxSamples+10; i++ {
+		h = recordUsage(h, now.Add(time.Duration(i)*time.Minute), uint64(i), 2000)
+	}
+	if len(h.Samples) != maxSamples {
+		t.Errorf("len(h.Samples) = %d, want %d", len(h.Samples), maxSamples)
+	}
+}
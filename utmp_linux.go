@@ -0,0 +1,60 @@
+//go:build linux && amd64
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log"
+	"os"
+)
+
+// utmpRecordSize and the field offsets below match the 64-bit glibc
+// layout of struct utmp (see utmp(5) and bits/utmp.h) as it exists on
+// amd64: sizeof(struct utmp) is 384 bytes there, not the 400 you'd get
+// by padding to an 8-byte-aligned guess, because glibc's
+// __WORDSIZE_TIME64_COMPAT32 shim keeps ut_session/ut_tv at 32 bits on
+// this biarch port. Whether that shim also applies to arm64 hasn't
+// been confirmed, so the build tag above is deliberately amd64-only;
+// utmp_linux_other.go covers arm64 and everything else on Linux via
+// getPtsFiles() until the arm64 layout is verified.
+const (
+	utmpRecordSize  = 384
+	utmpTypeOffset  = 0
+	utmpLineOffset  = 8
+	utmpLineSize    = 32
+	utmpUserProcess = 7
+)
+
+// getWallTTYs reads /var/run/utmp directly and returns the tty device
+// names (without the /dev/ prefix) of every active USER_PROCESS
+// session, i.e. the ttys a wall(1)-style broadcast should reach.
+func getWallTTYs() []string {
+	f, err := os.Open("/var/run/utmp")
+	if err != nil {
+		log.Println("Could not open /var/run/utmp")
+		return nil
+	}
+	defer f.Close()
+
+	r := make([]string, 0)
+	buf := make([]byte, utmpRecordSize)
+	for {
+		if _, err := io.ReadFull(f, buf); err != nil {
+			break
+		}
+		utType := binary.LittleEndian.Uint16(buf[utmpTypeOffset : utmpTypeOffset+2])
+		if utType != utmpUserProcess {
+			continue
+		}
+		line := buf[utmpLineOffset : utmpLineOffset+utmpLineSize]
+		if i := bytes.IndexByte(line, 0); i >= 0 {
+			line = line[:i]
+		}
+		if len(line) > 0 {
+			r = append(r, string(line))
+		}
+	}
+	return r
+}
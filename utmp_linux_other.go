@@ -0,0 +1,11 @@
+//go:build linux && !amd64
+
+package main
+
+// getWallTTYs falls back to the who(1)-based tty enumeration on Linux
+// architectures where the 64-bit glibc utmp record layout assumed by
+// utmp_linux.go doesn't apply (32-bit systems, non-glibc libcs, and
+// arm64 until its struct utmp layout is confirmed to match amd64's).
+func getWallTTYs() []string {
+	return getPtsFiles()
+}
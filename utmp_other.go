@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+// getWallTTYs falls back to the who(1)-based tty enumeration on
+// platforms where native utmp parsing isn't implemented.
+func getWallTTYs() []string {
+	return getPtsFiles()
+}